@@ -0,0 +1,48 @@
+package sensor
+
+import "math"
+
+// MagneticField describes a geomagnetic field vector in NED (north, east,
+// down) coordinates.
+type MagneticField struct {
+	// F is the total field intensity in microtesla.
+	F float64
+
+	// I is the inclination (dip angle) in radians, positive when the field
+	// points into the ground.
+	I float64
+
+	// D is the declination in radians, i.e. the angle between true north and
+	// magnetic north, measured clockwise around Down.
+	D float64
+}
+
+// defaultMagneticField is a mid-latitude approximation (roughly central
+// Europe) used when a Manager is created without WithGeoLocation or
+// WithMagneticField.
+var defaultMagneticField = MagneticField{F: 49, I: 1.05, D: 0}
+
+// MagneticFieldForLocation derives a simple dipole approximation of Earth's
+// magnetic field at the given geographic latitude/longitude, in degrees.
+// Inclination follows the dipole relation I = atan(2*tan(lat)), and total
+// intensity is interpolated between roughly 25µT at the magnetic equator and
+// 65µT near the magnetic poles. Declination is assumed to be zero; callers
+// who need WMM/IGRF-accurate declination should use WithMagneticField
+// instead. Longitude has no effect on this approximation and is accepted for
+// API symmetry with real-world geomagnetic models.
+func MagneticFieldForLocation(lat, lon float64) MagneticField {
+	latRad := lat * math.Pi / 180
+	incl := math.Atan(2 * math.Tan(latRad))
+	f := 25 + 40*math.Sin(math.Abs(incl))
+	return MagneticField{F: f, I: incl, D: 0}
+}
+
+// vector returns the field as a world-frame NED vector in microtesla.
+func (f MagneticField) vector() Vector3 {
+	horizontal := f.F * math.Cos(f.I)
+	return Vector3{
+		horizontal * math.Cos(f.D),
+		horizontal * math.Sin(f.D),
+		f.F * math.Sin(f.I),
+	}
+}
@@ -0,0 +1,243 @@
+package sensor
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/robin-samuel/spline"
+)
+
+// DefaultODR holds the assumed output data rate, in Hz, for each sensor
+// type. It is used both to scale NoiseDensity into a per-sample noise
+// standard deviation and, via Stream, as the default sampling rate.
+var DefaultODR = map[Type]float64{
+	Accelerometer:      200,
+	Gyroscope:          500,
+	Magnetometer:       50,
+	LinearAcceleration: 200,
+}
+
+// NoiseParams describes the noise characteristics of a single sensor
+// channel.
+type NoiseParams struct {
+	// NoiseDensity is the sensor's white-noise spectral density, in the
+	// channel's native units per √Hz (m/s²/√Hz for accel, rad/s/√Hz for
+	// gyro, µT/√Hz for magnetometer).
+	NoiseDensity float64
+
+	// BiasInstability is the 1σ amplitude, in the channel's native units, of
+	// the slow Gauss-Markov bias random walk superimposed on every reading.
+	BiasInstability float64
+
+	// BiasCorrelationTime is the time constant of the bias random walk.
+	BiasCorrelationTime time.Duration
+
+	// Misalignment is the axis misalignment/cross-coupling matrix applied
+	// to the reading before bias and noise are added. IdentityMatrix3 means
+	// perfectly orthogonal axes.
+	Misalignment Matrix3
+
+	// Resolution is the ADC quantization step, in the channel's native
+	// units. Zero disables quantization.
+	Resolution float64
+
+	// FullScale is the symmetric saturation limit, in the channel's native
+	// units. Zero disables clipping.
+	FullScale float64
+
+	// ODR is the output data rate, in Hz, used to scale NoiseDensity into a
+	// per-sample noise standard deviation.
+	ODR float64
+}
+
+// DefaultNoiseParams returns noise parameters approximating an
+// ICM-20948-class accelerometer, gyroscope, and magnetometer, sampled at the
+// given per-sensor output data rates. LinearAcceleration reuses the
+// accelerometer's parameters, since it is the same physical sensor with
+// gravity subtracted out.
+func DefaultNoiseParams(odr map[Type]float64) map[Type]NoiseParams {
+	accel := NoiseParams{
+		NoiseDensity:        300e-6 * earthGravity, // 300 µg/√Hz
+		BiasInstability:     0.01 * earthGravity,
+		BiasCorrelationTime: 100 * time.Second,
+		Misalignment:        IdentityMatrix3(),
+		Resolution:          16 * earthGravity / 32768, // 16 g, 16-bit FS
+		FullScale:           16 * earthGravity,
+		ODR:                 odr[Accelerometer],
+	}
+	linearAccel := accel
+	linearAccel.ODR = odr[LinearAcceleration]
+
+	return map[Type]NoiseParams{
+		Accelerometer:      accel,
+		LinearAcceleration: linearAccel,
+		Gyroscope: {
+			NoiseDensity:        0.015 * math.Pi / 180,
+			BiasInstability:     0.2 * math.Pi / 180,
+			BiasCorrelationTime: 100 * time.Second,
+			Misalignment:        IdentityMatrix3(),
+			Resolution:          2000 * math.Pi / 180 / 32768, // 2000 °/s, 16-bit FS
+			FullScale:           2000 * math.Pi / 180,
+			ODR:                 odr[Gyroscope],
+		},
+		Magnetometer: {
+			NoiseDensity:        0.4,
+			BiasInstability:     2,
+			BiasCorrelationTime: 60 * time.Second,
+			Misalignment:        IdentityMatrix3(),
+			Resolution:          0.15,
+			FullScale:           4912,
+			ODR:                 odr[Magnetometer],
+		},
+	}
+}
+
+const earthGravity = 9.80665
+
+// NoiseModel perturbs an otherwise-ideal sensor reading to emulate real MEMS
+// sensor behavior. Manager.Get applies it to every channel after computing
+// the clean value from the underlying position/orientation simulation.
+type NoiseModel interface {
+	// Apply returns a noisy version of data, a 3-element reading from the
+	// given sensor type sampled at time t.
+	Apply(st Type, t time.Time, data []float64) []float64
+}
+
+// noNoiseModel is a NoiseModel that passes readings through unchanged.
+type noNoiseModel struct{}
+
+func (noNoiseModel) Apply(st Type, t time.Time, data []float64) []float64 {
+	return data
+}
+
+// NoNoiseModel is a NoiseModel that passes readings through unchanged. Pass
+// it to WithNoiseModel to disable noise, bias, and quantization entirely.
+var NoNoiseModel NoiseModel = noNoiseModel{}
+
+// DefaultNoiseModel implements NoiseModel with Gaussian white noise, a
+// Gauss-Markov bias random walk, axis misalignment, and LSB quantization,
+// parameterized per sensor type by NoiseParams. The bias of each axis is a
+// spline over its own random-walk control points generated at construction
+// time, so repeated Apply calls at the same t return identical bias (and
+// hence, given the same *rand.Rand state elsewhere, identical noise is the
+// only non-reproducible component).
+type DefaultNoiseModel struct {
+	start time.Time
+	end   time.Time
+
+	params       map[Type]NoiseParams
+	bias         map[Type][3]spline.Spline
+	biasInterval map[Type]int64
+}
+
+// NewDefaultNoiseModel returns a DefaultNoiseModel covering [start, end] for
+// the given per-sensor-type parameters. Sensor types absent from params are
+// passed through unperturbed.
+func NewDefaultNoiseModel(start, end time.Time, params map[Type]NoiseParams) *DefaultNoiseModel {
+	n := &DefaultNoiseModel{
+		start:        start,
+		end:          end,
+		params:       params,
+		bias:         map[Type][3]spline.Spline{},
+		biasInterval: map[Type]int64{},
+	}
+	duration := end.Sub(start)
+	for st, p := range params {
+		interval := biasControlPointInterval(p, duration)
+		var axes [3]spline.Spline
+		for i := range axes {
+			axes[i], _ = spline.NewSpline(spline.CatmullRom, randomControlPointsBias(p, duration, interval))
+		}
+		n.bias[st] = axes
+		n.biasInterval[st] = interval
+	}
+	return n
+}
+
+// biasControlPointInterval returns the spacing, in milliseconds, between
+// randomControlPointsBias's control points for p over a simulation of
+// duration d: one tenth of the correlation time, clamped so the spline
+// always gets enough control points to build regardless of how long
+// BiasCorrelationTime is relative to d.
+func biasControlPointInterval(p NoiseParams, d time.Duration) int64 {
+	interval := p.BiasCorrelationTime.Milliseconds() / 10
+	if interval <= 0 {
+		interval = 1000
+	}
+	if maxInterval := d.Milliseconds() / 10; maxInterval > 0 && interval > maxInterval {
+		interval = maxInterval
+	}
+	if interval <= 0 {
+		interval = 1
+	}
+	return interval
+}
+
+// randomControlPointsBias generates control points for a discretized
+// first-order Gauss-Markov (Ornstein-Uhlenbeck) process: it mean-reverts to
+// zero with time constant p.BiasCorrelationTime and has steady-state
+// standard deviation p.BiasInstability. Control points are spaced interval
+// milliseconds apart, indexed 0, 1, 2, ... rather than by elapsed time, to
+// match spline.Spline.At's control-point-index argument.
+func randomControlPointsBias(p NoiseParams, d time.Duration, interval int64) []spline.Point {
+	var points []spline.Point
+
+	theta := 1 / p.BiasCorrelationTime.Seconds()
+
+	value := (rand.Float64() - 0.5) * 2 * p.BiasInstability
+	for i, index := int64(0), 0.0; i < d.Milliseconds(); i, index = i+interval, index+1 {
+		dt := float64(interval) / 1000
+		value += -theta*value*dt + p.BiasInstability*math.Sqrt(2*theta*dt)*rand.NormFloat64()
+		points = append(points, spline.Point{
+			X: index,
+			Y: value,
+		})
+	}
+	return points
+}
+
+// Apply implements NoiseModel.
+func (n *DefaultNoiseModel) Apply(st Type, t time.Time, data []float64) []float64 {
+	p, ok := n.params[st]
+	if !ok || len(data) != 3 {
+		return data
+	}
+
+	if t.Before(n.start) {
+		t = n.start
+	}
+	if t.After(n.end) {
+		t = n.end
+	}
+	elapsed := float64(t.Sub(n.start).Milliseconds())
+	biasIndex := elapsed / float64(n.biasInterval[st])
+
+	v := p.Misalignment.Apply(Vector3{data[0], data[1], data[2]})
+	sigma := p.NoiseDensity * math.Sqrt(p.ODR)
+	bias := n.bias[st]
+
+	out := make([]float64, 3)
+	for i := 0; i < 3; i++ {
+		value := v[i] + bias[i].At(biasIndex).Y + sigma*rand.NormFloat64()
+		if p.Resolution > 0 {
+			value = math.Round(value/p.Resolution) * p.Resolution
+		}
+		if p.FullScale > 0 {
+			value = clamp(value, p.FullScale)
+		}
+		out[i] = value
+	}
+	return out
+}
+
+// clamp restricts value to [-limit, limit].
+func clamp(value, limit float64) float64 {
+	if value > limit {
+		return limit
+	}
+	if value < -limit {
+		return -limit
+	}
+	return value
+}
@@ -0,0 +1,331 @@
+package sensor
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/robin-samuel/spline"
+)
+
+// defaultPositionInterval and defaultOrientationInterval are the spacing, in
+// milliseconds, between generated position and orientation control points.
+// They match the values NewSimulator has always used.
+const (
+	defaultPositionInterval    = 50
+	defaultOrientationInterval = 100
+)
+
+// ActivityProfile generates spline control points for device position and
+// orientation, in place of the generic activity-scalar random walk used by
+// NewSimulator. Position axes are 0=x, 1=y, 2=z (millimeters); orientation
+// axes are 0=pitch, 1=roll, 2=yaw (radians), matching Position.Values and
+// Orientation.Values.
+type ActivityProfile interface {
+	// Position returns control points for the given position axis over
+	// duration d.
+	Position(axis int, d time.Duration) []spline.Point
+
+	// Orientation returns control points for the given orientation axis
+	// over duration d.
+	Orientation(axis int, d time.Duration) []spline.Point
+}
+
+// NewSimulatorFromProfile returns a new Simulator whose position and
+// orientation curves are generated by profile. Passing a *Timeline lets a
+// single simulated run move through several activities.
+func NewSimulatorFromProfile(start, end time.Time, profile ActivityProfile) *Simulator {
+	duration := end.Sub(start)
+
+	sim := &Simulator{
+		start:               start,
+		end:                 end,
+		positionInterval:    defaultPositionInterval,
+		orientationInterval: defaultOrientationInterval,
+	}
+
+	s1, _ := spline.NewSpline(spline.CatmullRom, profile.Position(0, duration))
+	s2, _ := spline.NewSpline(spline.CatmullRom, profile.Position(1, duration))
+	s3, _ := spline.NewSpline(spline.CatmullRom, profile.Position(2, duration))
+
+	s4, _ := spline.NewSpline(spline.CatmullRom, profile.Orientation(0, duration))
+	s5, _ := spline.NewSpline(spline.CatmullRom, profile.Orientation(1, duration))
+	s6, _ := spline.NewSpline(spline.CatmullRom, profile.Orientation(2, duration))
+
+	sim.positionCurveX, sim.positionCurveY, sim.positionCurveZ = s1, s2, s3
+	sim.orientationCurveX, sim.orientationCurveY, sim.orientationCurveZ = s4, s5, s6
+
+	return sim
+}
+
+// timelineSegment is one leg of a Timeline: profile drives motion from
+// start to end, offsets from the beginning of the timeline.
+type timelineSegment struct {
+	start, end time.Duration
+	profile    ActivityProfile
+}
+
+// Timeline composes several ActivityProfiles over disjoint time windows into
+// a single ActivityProfile, letting a simulated run move through multiple
+// activities, e.g. walking to a desk and then sitting down to type:
+//
+//	sensor.NewTimeline().
+//		Add(0, 30*time.Second, sensor.Walking()).
+//		Add(30*time.Second, 5*time.Minute, sensor.Typing())
+type Timeline struct {
+	segments []timelineSegment
+}
+
+// NewTimeline returns an empty Timeline.
+func NewTimeline() *Timeline {
+	return &Timeline{}
+}
+
+// Add appends a segment running from start to end, offsets from the
+// beginning of the timeline, driven by profile. Segments should be added in
+// increasing, non-overlapping order; gaps are left flat. Add returns tl so
+// calls can be chained.
+func (tl *Timeline) Add(start, end time.Duration, profile ActivityProfile) *Timeline {
+	tl.segments = append(tl.segments, timelineSegment{start: start, end: end, profile: profile})
+	return tl
+}
+
+// Position implements ActivityProfile.
+func (tl *Timeline) Position(axis int, d time.Duration) []spline.Point {
+	return tl.points(axis, defaultPositionInterval, ActivityProfile.Position)
+}
+
+// Orientation implements ActivityProfile.
+func (tl *Timeline) Orientation(axis int, d time.Duration) []spline.Point {
+	return tl.points(axis, defaultOrientationInterval, ActivityProfile.Orientation)
+}
+
+// points concatenates every segment's control points, time-shifted by the
+// segment's own offset into the timeline, holding flat at the last known
+// value (zero, before the first segment) across any gap between segments.
+// interval is the same control-point spacing the caller samples fn's output
+// at (defaultPositionInterval or defaultOrientationInterval), so the flat
+// hold has the right number of points to span its gap. The timeline's total
+// duration is whatever its segments span, rather than a d passed in by the
+// caller.
+func (tl *Timeline) points(axis int, interval int, fn func(ActivityProfile, int, time.Duration) []spline.Point) []spline.Point {
+	var points []spline.Point
+	var elapsed time.Duration
+	var last float64
+
+	for _, seg := range tl.segments {
+		segDuration := seg.end - seg.start
+		if segDuration <= 0 {
+			continue
+		}
+		if gap := seg.start - elapsed; gap > 0 {
+			points = append(points, flatPoints(gap, interval, last, 0)...)
+		}
+
+		offset := float64(seg.start.Milliseconds())
+		segPoints := fn(seg.profile, axis, segDuration)
+		for _, p := range segPoints {
+			points = append(points, spline.Point{X: p.X + offset, Y: p.Y})
+		}
+		if len(segPoints) > 0 {
+			last = segPoints[len(segPoints)-1].Y
+		}
+		elapsed = seg.end
+	}
+	return points
+}
+
+// sinusoidPoints generates control points for a sine wave of the given
+// frequency (Hz) and amplitude, evaluated every interval milliseconds over
+// duration d, with a small amount of high-frequency jitter added for
+// realism.
+func sinusoidPoints(d time.Duration, interval int, freqHz, amplitude, phase, offset, jitter float64) []spline.Point {
+	var points []spline.Point
+	for i := 0; i < int(d.Milliseconds()); i += interval {
+		t := float64(i) / 1000
+		value := amplitude*math.Sin(2*math.Pi*freqHz*t+phase) + offset + (rand.Float64()-0.5)*jitter
+		points = append(points, spline.Point{X: float64(i), Y: value})
+	}
+	if len(points) < 2 {
+		points = append(points, spline.Point{X: float64(d.Milliseconds()), Y: offset})
+	}
+	return points
+}
+
+// flatPoints generates control points that stay at offset, with a small
+// amount of high-frequency jitter added for realism.
+func flatPoints(d time.Duration, interval int, offset, jitter float64) []spline.Point {
+	var points []spline.Point
+	for i := 0; i < int(d.Milliseconds()); i += interval {
+		points = append(points, spline.Point{X: float64(i), Y: offset + (rand.Float64()-0.5)*jitter})
+	}
+	if len(points) < 2 {
+		points = append(points, spline.Point{X: float64(d.Milliseconds()), Y: offset})
+	}
+	return points
+}
+
+// gaitProfile shares the step-phase math behind Walking and Running, which
+// differ only in bounce frequency and amplitude.
+type gaitProfile struct {
+	phase float64
+
+	bounceFreq, bounceAmplitude float64 // vertical (position z), Hz and mm
+	swayFreq, swayAmplitude     float64 // lateral (position x), Hz and mm
+	nodAmplitude                float64 // pitch nod locked to bounceFreq, radians
+}
+
+func (p *gaitProfile) Position(axis int, d time.Duration) []spline.Point {
+	switch axis {
+	case 0: // x: lateral sway
+		return sinusoidPoints(d, defaultPositionInterval, p.swayFreq, p.swayAmplitude, p.phase, 0, 1)
+	case 2: // z: vertical bounce
+		return sinusoidPoints(d, defaultPositionInterval, p.bounceFreq, p.bounceAmplitude, p.phase, 0, 1)
+	default: // y: forward travel, no meaningful short-term oscillation
+		return flatPoints(d, defaultPositionInterval, 0, 2)
+	}
+}
+
+func (p *gaitProfile) Orientation(axis int, d time.Duration) []spline.Point {
+	if axis == 0 { // pitch nod, one nod per bounce
+		return sinusoidPoints(d, defaultOrientationInterval, p.bounceFreq, p.nodAmplitude, p.phase, 0, 0.002)
+	}
+	return flatPoints(d, defaultOrientationInterval, 0, 0.002)
+}
+
+// Walking returns an ActivityProfile for a phone held by someone walking:
+// a ~2 Hz vertical bounce of about ±3 cm, a ~1 Hz lateral sway, and a pitch
+// nod locked to the same gait phase.
+func Walking() ActivityProfile {
+	return &gaitProfile{
+		phase:           rand.Float64() * 2 * math.Pi,
+		bounceFreq:      2,
+		bounceAmplitude: 30,
+		swayFreq:        1,
+		swayAmplitude:   20,
+		nodAmplitude:    0.05,
+	}
+}
+
+// Running returns an ActivityProfile for a phone held by someone running: a
+// ~3 Hz vertical bounce with a larger amplitude than Walking, plus a larger
+// gait-locked pitch nod.
+func Running() ActivityProfile {
+	return &gaitProfile{
+		phase:           rand.Float64() * 2 * math.Pi,
+		bounceFreq:      3,
+		bounceAmplitude: 60,
+		swayFreq:        1.5,
+		swayAmplitude:   40,
+		nodAmplitude:    0.12,
+	}
+}
+
+// typingProfile is a tiny, high-frequency hand tremor with the device held
+// nearly flat on a desk.
+type typingProfile struct {
+	phase float64
+}
+
+// Typing returns an ActivityProfile for a phone resting on a desk while its
+// owner types: sub-millimeter, high-frequency micro-tremor and a nearly flat
+// orientation.
+func Typing() ActivityProfile {
+	return &typingProfile{phase: rand.Float64() * 2 * math.Pi}
+}
+
+func (p *typingProfile) Position(axis int, d time.Duration) []spline.Point {
+	const tremorFreq = 6.0 // Hz, keystroke-induced micro-tremor
+	return sinusoidPoints(d, defaultPositionInterval, tremorFreq, 0.4, p.phase+float64(axis), 0, 0.2)
+}
+
+func (p *typingProfile) Orientation(axis int, d time.Duration) []spline.Point {
+	return flatPoints(d, defaultOrientationInterval, 0, 0.0005)
+}
+
+// idleProfile is a resting hand tremor: slightly larger and lower-frequency
+// than Typing, with no deliberate motion.
+type idleProfile struct {
+	phase float64
+}
+
+// Idle returns an ActivityProfile for a phone held still in a resting hand:
+// sub-millimeter hand tremor around 8-12 Hz and an essentially fixed
+// orientation.
+func Idle() ActivityProfile {
+	return &idleProfile{phase: rand.Float64() * 2 * math.Pi}
+}
+
+func (p *idleProfile) Position(axis int, d time.Duration) []spline.Point {
+	tremorFreq := 8 + rand.Float64()*4 // 8-12 Hz hand tremor
+	return sinusoidPoints(d, defaultPositionInterval, tremorFreq, 0.3, p.phase+float64(axis), 0, 0.1)
+}
+
+func (p *idleProfile) Orientation(axis int, d time.Duration) []spline.Point {
+	return flatPoints(d, defaultOrientationInterval, 0, 0.0003)
+}
+
+// inPocketProfile holds the phone face-in at a steep, fixed pitch and
+// damps gait motion the way clothing would.
+type inPocketProfile struct {
+	gait *gaitProfile
+}
+
+// InPocket returns an ActivityProfile for a phone carried in a pocket while
+// its owner walks: the device sits at a steep, fixed pitch with walking's
+// gait-driven motion heavily damped by clothing.
+func InPocket() ActivityProfile {
+	return &inPocketProfile{
+		gait: &gaitProfile{
+			phase:           rand.Float64() * 2 * math.Pi,
+			bounceFreq:      2,
+			bounceAmplitude: 10,
+			swayFreq:        1,
+			swayAmplitude:   6,
+			nodAmplitude:    0.02,
+		},
+	}
+}
+
+func (p *inPocketProfile) Position(axis int, d time.Duration) []spline.Point {
+	return p.gait.Position(axis, d)
+}
+
+func (p *inPocketProfile) Orientation(axis int, d time.Duration) []spline.Point {
+	if axis == 0 { // pitch: phone standing nearly on end, pocket opening up
+		points := p.gait.Orientation(axis, d)
+		for i := range points {
+			points[i].Y += 1.4
+		}
+		return points
+	}
+	return p.gait.Orientation(axis, d)
+}
+
+// drivingProfile overlays road vibration on a fixed dashboard-mount pitch.
+type drivingProfile struct {
+	phase float64
+}
+
+// Driving returns an ActivityProfile for a phone in a dashboard mount: a
+// fixed mounting pitch with low-frequency road vibration overlaid.
+func Driving() ActivityProfile {
+	return &drivingProfile{phase: rand.Float64() * 2 * math.Pi}
+}
+
+func (p *drivingProfile) Position(axis int, d time.Duration) []spline.Point {
+	const vibrationFreq = 12.0 // Hz, road/engine vibration
+	switch axis {
+	case 2:
+		return sinusoidPoints(d, defaultPositionInterval, vibrationFreq, 1.5, p.phase, 0, 0.5)
+	default:
+		return sinusoidPoints(d, defaultPositionInterval, vibrationFreq*0.5, 0.5, p.phase+float64(axis), 0, 0.3)
+	}
+}
+
+func (p *drivingProfile) Orientation(axis int, d time.Duration) []spline.Point {
+	if axis == 0 { // pitch: fixed dashboard mount angle
+		return flatPoints(d, defaultOrientationInterval, 0.35, 0.003)
+	}
+	return flatPoints(d, defaultOrientationInterval, 0, 0.003)
+}
@@ -0,0 +1,136 @@
+package sensor
+
+import "math"
+
+// Quaternion is a unit quaternion (w + xi + yj + zk) representing a 3D
+// rotation.
+type Quaternion struct {
+	W, X, Y, Z float64
+}
+
+// QuaternionIdentity returns the identity rotation.
+func QuaternionIdentity() Quaternion {
+	return Quaternion{W: 1}
+}
+
+// QuaternionFromEuler builds a unit quaternion from pitch (rotation around
+// x), roll (around y), and yaw (around z), composed in the same z*y*x order
+// as Quaternion.RotationMatrix.
+func QuaternionFromEuler(pitch, roll, yaw float64) Quaternion {
+	sx, cx := math.Sincos(pitch * 0.5)
+	sy, cy := math.Sincos(roll * 0.5)
+	sz, cz := math.Sincos(yaw * 0.5)
+
+	return Quaternion{
+		W: cx*cy*cz + sx*sy*sz,
+		X: sx*cy*cz - cx*sy*sz,
+		Y: cx*sy*cz + sx*cy*sz,
+		Z: cx*cy*sz - sx*sy*cz,
+	}
+}
+
+// Euler extracts pitch (around x), roll (around y), and yaw (around z) from
+// q, the inverse of QuaternionFromEuler.
+func (q Quaternion) Euler() (pitch, roll, yaw float64) {
+	sinPitch := 2 * (q.W*q.X + q.Y*q.Z)
+	cosPitch := 1 - 2*(q.X*q.X+q.Y*q.Y)
+	pitch = math.Atan2(sinPitch, cosPitch)
+
+	sinRoll := 2 * (q.W*q.Y - q.Z*q.X)
+	switch {
+	case sinRoll >= 1:
+		roll = math.Pi / 2
+	case sinRoll <= -1:
+		roll = -math.Pi / 2
+	default:
+		roll = math.Asin(sinRoll)
+	}
+
+	sinYaw := 2 * (q.W*q.Z + q.X*q.Y)
+	cosYaw := 1 - 2*(q.Y*q.Y+q.Z*q.Z)
+	yaw = math.Atan2(sinYaw, cosYaw)
+
+	return pitch, roll, yaw
+}
+
+// Normalize returns q scaled to unit length.
+func (q Quaternion) Normalize() Quaternion {
+	n := math.Sqrt(q.W*q.W + q.X*q.X + q.Y*q.Y + q.Z*q.Z)
+	if n == 0 {
+		return QuaternionIdentity()
+	}
+	return Quaternion{W: q.W / n, X: q.X / n, Y: q.Y / n, Z: q.Z / n}
+}
+
+// Conjugate returns the conjugate of q, equal to its inverse for unit
+// quaternions.
+func (q Quaternion) Conjugate() Quaternion {
+	return Quaternion{W: q.W, X: -q.X, Y: -q.Y, Z: -q.Z}
+}
+
+// Negate flips the sign of every component. A quaternion and its negation
+// represent the same rotation but follow opposite arcs when interpolated.
+func (q Quaternion) Negate() Quaternion {
+	return Quaternion{W: -q.W, X: -q.X, Y: -q.Y, Z: -q.Z}
+}
+
+// Dot returns the dot product of q and r.
+func (q Quaternion) Dot(r Quaternion) float64 {
+	return q.W*r.W + q.X*r.X + q.Y*r.Y + q.Z*r.Z
+}
+
+// RotationMatrix returns the body-to-world rotation matrix represented by
+// q.
+func (q Quaternion) RotationMatrix() Matrix3 {
+	ww, xx, yy, zz := q.W*q.W, q.X*q.X, q.Y*q.Y, q.Z*q.Z
+	wx, wy, wz := q.W*q.X, q.W*q.Y, q.W*q.Z
+	xy, xz, yz := q.X*q.Y, q.X*q.Z, q.Y*q.Z
+
+	return Matrix3{
+		{ww + xx - yy - zz, 2 * (xy - wz), 2 * (xz + wy)},
+		{2 * (xy + wz), ww - xx + yy - zz, 2 * (yz - wx)},
+		{2 * (xz - wy), 2 * (yz + wx), ww - xx - yy + zz},
+	}
+}
+
+// Multiply returns the Hamilton product q*r.
+func (q Quaternion) Multiply(r Quaternion) Quaternion {
+	return Quaternion{
+		W: q.W*r.W - q.X*r.X - q.Y*r.Y - q.Z*r.Z,
+		X: q.W*r.X + q.X*r.W + q.Y*r.Z - q.Z*r.Y,
+		Y: q.W*r.Y - q.X*r.Z + q.Y*r.W + q.Z*r.X,
+		Z: q.W*r.Z + q.X*r.Y - q.Y*r.X + q.Z*r.W,
+	}
+}
+
+// Slerp spherically interpolates between q and r by t in [0, 1], always
+// choosing the shorter arc.
+func Slerp(q, r Quaternion, t float64) Quaternion {
+	if q.Dot(r) < 0 {
+		r = r.Negate()
+	}
+
+	cosTheta := q.Dot(r)
+	if cosTheta > 0.9995 {
+		// q and r are nearly colinear; fall back to a normalized linear
+		// interpolation to avoid dividing by a near-zero sine below.
+		return Quaternion{
+			W: q.W + t*(r.W-q.W),
+			X: q.X + t*(r.X-q.X),
+			Y: q.Y + t*(r.Y-q.Y),
+			Z: q.Z + t*(r.Z-q.Z),
+		}.Normalize()
+	}
+
+	theta := math.Acos(cosTheta)
+	sinTheta := math.Sin(theta)
+	a := math.Sin((1-t)*theta) / sinTheta
+	b := math.Sin(t*theta) / sinTheta
+
+	return Quaternion{
+		W: a*q.W + b*r.W,
+		X: a*q.X + b*r.X,
+		Y: a*q.Y + b*r.Y,
+		Z: a*q.Z + b*r.Z,
+	}
+}
@@ -12,9 +12,10 @@ import (
 type Type int
 
 var sensorNames = map[Type]string{
-	Accelerometer: "Accelerometer",
-	Gyroscope:     "Gyroscope",
-	Magnetometer:  "Magnetometer",
+	Accelerometer:      "Accelerometer",
+	Gyroscope:          "Gyroscope",
+	Magnetometer:       "Magnetometer",
+	LinearAcceleration: "LinearAcceleration",
 }
 
 // String returns the string representation of the sensor type.
@@ -29,6 +30,24 @@ const (
 	Accelerometer Type = iota
 	Gyroscope
 	Magnetometer
+
+	// LinearAcceleration is a derived channel equivalent to Android's
+	// TYPE_LINEAR_ACCELERATION: the same world-frame linear acceleration as
+	// Accelerometer, rotated into the body frame, but with gravity removed.
+	LinearAcceleration
+)
+
+// ReferenceFrame selects the world-frame axis convention used for gravity
+// and the simulated magnetic field.
+type ReferenceFrame int
+
+const (
+	// ENU is the East-North-Up convention: z points away from the ground,
+	// so gravity acts along -z.
+	ENU ReferenceFrame = iota
+	// NED is the North-East-Down convention: z points into the ground, so
+	// gravity acts along +z.
+	NED
 )
 
 type Event struct {
@@ -41,9 +60,14 @@ type Event struct {
 	// Data is the event data.
 	//
 	// If the event source is Accelerometer,
-	//  - Data[0]: acceleration force in x axis in m/s^2
-	//  - Data[1]: acceleration force in y axis in m/s^2
-	//  - Data[2]: acceleration force in z axis in m/s^2
+	//  - Data[0]: acceleration force (including gravity) in x axis in m/s^2
+	//  - Data[1]: acceleration force (including gravity) in y axis in m/s^2
+	//  - Data[2]: acceleration force (including gravity) in z axis in m/s^2
+	//
+	// If the event source is LinearAcceleration,
+	//  - Data[0]: acceleration force (excluding gravity) in x axis in m/s^2
+	//  - Data[1]: acceleration force (excluding gravity) in y axis in m/s^2
+	//  - Data[2]: acceleration force (excluding gravity) in z axis in m/s^2
 	//
 	// If the event source is Gyroscope,
 	//  - Data[0]: rate of rotation around the x axis in rad/s
@@ -51,9 +75,9 @@ type Event struct {
 	//  - Data[2]: rate of rotation around the z axis in rad/s
 	//
 	// If the event source is Magnetometer,
-	//  - Data[0]: force of gravity along the x axis in m/s^2
-	//  - Data[1]: force of gravity along the y axis in m/s^2
-	//  - Data[2]: force of gravity along the z axis in m/s^2
+	//  - Data[0]: ambient magnetic field along the x axis in microtesla
+	//  - Data[1]: ambient magnetic field along the y axis in microtesla
+	//  - Data[2]: ambient magnetic field along the z axis in microtesla
 	//
 	Data []float64
 }
@@ -62,16 +86,136 @@ type Manager struct {
 	start time.Time
 	end   time.Time
 	sim   *Simulator
+
+	magneticField MagneticField
+	hardIron      Vector3
+	softIron      Matrix3
+
+	gravity        float64
+	referenceFrame ReferenceFrame
+
+	noise NoiseModel
+}
+
+// Option configures optional Manager behavior.
+type Option func(*Manager)
+
+// WithGeoLocation derives the simulated magnetic field from the given
+// geographic latitude/longitude (in degrees) using a simple dipole
+// approximation of Earth's field. See MagneticFieldForLocation.
+func WithGeoLocation(lat, lon float64) Option {
+	return func(m *Manager) {
+		m.magneticField = MagneticFieldForLocation(lat, lon)
+	}
+}
+
+// WithMagneticField overrides the simulated magnetic field directly, letting
+// callers plug in WMM/IGRF-derived coefficients (F: total intensity in
+// microtesla, I: inclination in radians, D: declination in radians) instead
+// of the built-in dipole approximation.
+func WithMagneticField(f, i, d float64) Option {
+	return func(m *Manager) {
+		m.magneticField = MagneticField{F: f, I: i, D: d}
+	}
+}
+
+// WithHardIron adds a constant body-frame offset (in microtesla) to the
+// simulated magnetometer reading, modeling hard-iron distortion from nearby
+// ferrous material or onboard circuitry.
+func WithHardIron(x, y, z float64) Option {
+	return func(m *Manager) {
+		m.hardIron = Vector3{x, y, z}
+	}
+}
+
+// WithSoftIron scales and cross-couples the simulated magnetometer reading
+// through the given 3x3 matrix, modeling soft-iron distortion. The zero
+// value of Matrix3 is not a valid argument; use IdentityMatrix3 as a base
+// for small perturbations.
+func WithSoftIron(matrix Matrix3) Option {
+	return func(m *Manager) {
+		m.softIron = matrix
+	}
+}
+
+// WithNoiseModel overrides the noise, bias, and quantization model applied
+// to every channel in Get. Pass NoNoiseModel to return clean, unperturbed
+// readings.
+func WithNoiseModel(model NoiseModel) Option {
+	return func(m *Manager) {
+		m.noise = model
+	}
+}
+
+// WithGravity overrides the gravity magnitude, in m/s², added to the
+// simulated Accelerometer reading (default earthGravity, standard gravity).
+// Pass 0 to disable gravity, e.g. for tests written against the old
+// gravity-free behavior.
+func WithGravity(g float64) Option {
+	return func(m *Manager) {
+		m.gravity = g
+	}
+}
+
+// WithReferenceFrame selects the world-frame axis convention used for
+// gravity and the simulated magnetic field. The default is ENU.
+func WithReferenceFrame(frame ReferenceFrame) Option {
+	return func(m *Manager) {
+		m.referenceFrame = frame
+	}
 }
 
-func NewManager(start time.Time, end time.Time, activity float64) *Manager {
+func NewManager(start time.Time, end time.Time, activity float64, opts ...Option) *Manager {
+	m := newManagerDefaults(start, end)
+	m.sim = NewSimulator(start.Add(-time.Second), end.Add(time.Second), activity)
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// NewManagerFromProfile returns a new Manager whose device motion is driven
+// by profile instead of the generic activity-scalar random walk used by
+// NewManager. Pass a *Timeline to move through several activities over the
+// course of one simulated run.
+func NewManagerFromProfile(start, end time.Time, profile ActivityProfile, opts ...Option) *Manager {
+	m := newManagerDefaults(start, end)
+	m.sim = NewSimulatorFromProfile(start.Add(-time.Second), end.Add(time.Second), profile)
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// newManagerDefaults returns a Manager with every option default set except
+// sim, which the caller fills in with either NewSimulator or
+// NewSimulatorFromProfile.
+func newManagerDefaults(start, end time.Time) *Manager {
 	return &Manager{
-		start: start,
-		end:   end,
-		sim:   NewSimulator(start.Add(-time.Second), end.Add(time.Second), activity),
+		start:         start,
+		end:           end,
+		magneticField: defaultMagneticField,
+		softIron:      IdentityMatrix3(),
+		gravity:       earthGravity,
+		noise:         NewDefaultNoiseModel(start, end, DefaultNoiseParams(DefaultODR)),
 	}
 }
 
+// gravityVector returns the gravity vector in the Manager's configured
+// reference frame.
+func (s *Manager) gravityVector() Vector3 {
+	return s.toReferenceFrame(Vector3{0, 0, s.gravity})
+}
+
+// toReferenceFrame converts a world vector expressed in NED (north, east,
+// down) into the Manager's configured reference frame.
+func (s *Manager) toReferenceFrame(ned Vector3) Vector3 {
+	if s.referenceFrame == ENU {
+		return Vector3{ned[1], ned[0], -ned[2]}
+	}
+	return ned
+}
+
 func (s *Manager) Start() time.Time {
 	return s.start
 }
@@ -84,6 +228,13 @@ func (s *Manager) Orientation(t time.Time) Orientation {
 	return s.sim.Orientation(t)
 }
 
+// OrientationQuat returns the device's orientation at t as a unit
+// quaternion. Unlike Orientation, it carries no ±π wrap discontinuity, so
+// it is the right representation to difference for angular velocity.
+func (s *Manager) OrientationQuat(t time.Time) Quaternion {
+	return s.sim.OrientationQuat(t)
+}
+
 func (s *Manager) Position(t time.Time) Position {
 	return s.sim.Position(t)
 }
@@ -94,48 +245,71 @@ func (s *Manager) Get(st Type, t time.Time) Event {
 	ori0 := s.sim.Orientation(t.Add(-time.Millisecond * 66))
 	ori1 := s.sim.Orientation(t)
 
+	var data []float64
+	var timestamp time.Time
+
 	switch st {
-	case Accelerometer:
+	case Accelerometer, LinearAcceleration:
 		// calculate position delta and convert millimeters to meters
 		positionD := []float64{
 			(pos1.Values[0] - pos0.Values[0]) / 1000,
 			(pos1.Values[1] - pos0.Values[1]) / 1000,
 			(pos1.Values[2] - pos0.Values[2]) / 1000,
 		}
-		// calculate acceleration
+		// calculate world-frame linear acceleration
 		timeD := pos1.Timestamp.Sub(pos0.Timestamp).Seconds()
-		acceleration := []float64{
+		aWorld := Vector3{
 			positionD[0] / timeD / timeD,
 			positionD[1] / timeD / timeD,
 			positionD[2] / timeD / timeD,
 		}
-		return Event{
-			Sensor:    Accelerometer,
-			Timestamp: pos1.Timestamp,
-			Data:      acceleration,
+		if st == Accelerometer {
+			// a_body = Rᵀ·(a_world - g_world): a stationary, flat device
+			// reads +g on the up axis, and free fall (a_world == g_world)
+			// reads ~0, as a real accelerometer would.
+			aWorld = aWorld.Subtract(s.gravityVector())
 		}
+		body := s.sim.OrientationQuat(t).RotationMatrix().Transpose().Apply(aWorld)
+		data = []float64{body[0], body[1], body[2]}
+		timestamp = pos1.Timestamp
 	case Gyroscope:
-		// calculate orientation delta
-		oientationD := []float64{
-			ori1.Values[0] - ori0.Values[0],
-			ori1.Values[1] - ori0.Values[1],
-			ori1.Values[2] - ori0.Values[2],
+		q0 := s.sim.OrientationQuat(t.Add(-time.Millisecond * 66))
+		q1 := s.sim.OrientationQuat(t)
+		if q0.Dot(q1) < 0 {
+			q1 = q1.Negate()
 		}
-		// calculate angular velocity
+		// angular velocity in the body frame, which is what a physical
+		// gyroscope measures: ω = 2·(q0⁻¹·q1).vector/Δt
+		dq := q0.Conjugate().Multiply(q1)
 		timeD := ori1.Timestamp.Sub(ori0.Timestamp).Seconds()
-		angularVelocity := []float64{
-			oientationD[0] / timeD,
-			oientationD[1] / timeD,
-			oientationD[2] / timeD,
-		}
-		return Event{
-			Sensor:    Gyroscope,
-			Timestamp: ori1.Timestamp,
-			Data:      angularVelocity,
+		data = []float64{
+			2 * dq.X / timeD,
+			2 * dq.Y / timeD,
+			2 * dq.Z / timeD,
 		}
+		timestamp = ori1.Timestamp
+	case Magnetometer:
+		worldField := s.toReferenceFrame(s.magneticField.vector())
+		bodyField := s.sim.OrientationQuat(t).RotationMatrix().Transpose().Apply(worldField)
+		// distorted = A·field + b: the soft-iron matrix applies to the clean
+		// field only, and the hard-iron offset is added afterward, per the
+		// standard hard/soft-iron calibration model.
+		distorted := s.softIron.Apply(bodyField).Add(s.hardIron)
+		data = []float64{distorted[0], distorted[1], distorted[2]}
+		timestamp = ori1.Timestamp
 	default:
 		return Event{}
 	}
+
+	if s.noise != nil {
+		data = s.noise.Apply(st, t, data)
+	}
+
+	return Event{
+		Sensor:    st,
+		Timestamp: timestamp,
+		Data:      data,
+	}
 }
 
 // Position is a 3D vector representing the position of the device.
@@ -187,7 +361,7 @@ func NewSimulator(start, end time.Time, activity float64) *Simulator {
 
 	s0, _ := spline.NewSpline(spline.Bspline, randomControlPointsActivity(activity, duration))
 
-	sim := &Simulator{start: start, end: end, activity: activity, activityCurve: s0, positionInterval: 50, orientationInterval: 100}
+	sim := &Simulator{start: start, end: end, activity: activity, activityCurve: s0, positionInterval: defaultPositionInterval, orientationInterval: defaultOrientationInterval}
 
 	s1, _ := spline.NewSpline(spline.CatmullRom, sim.randomControlPointsPosition(0, duration, -5.0, 5.0))
 	s2, _ := spline.NewSpline(spline.CatmullRom, sim.randomControlPointsPosition(0, duration, -5.0, 5.0))
@@ -285,6 +459,9 @@ func (s *Simulator) Position(t time.Time) Position {
 	}
 }
 
+// Orientation returns the device's orientation at t as Euler angles,
+// derived from OrientationQuat so it never sees the spurious ±π wrap a
+// finite difference of raw Euler angles would produce.
 func (s *Simulator) Orientation(t time.Time) Orientation {
 	if t.Before(s.start) {
 		t = s.start
@@ -292,25 +469,41 @@ func (s *Simulator) Orientation(t time.Time) Orientation {
 	if t.After(s.end) {
 		t = s.end
 	}
-	i := float64(t.Sub(s.start).Milliseconds()) / float64(s.orientationInterval)
+	pitch, roll, yaw := s.OrientationQuat(t).Euler()
 	return Orientation{
 		Timestamp: t,
-		Values: []float64{
-			betweenPi(float64(s.orientationCurveX.At(i).Y)),
-			betweenPi(float64(s.orientationCurveY.At(i).Y)),
-			betweenPi(float64(s.orientationCurveZ.At(i).Y)),
-		},
+		Values:    []float64{pitch, roll, yaw},
 	}
 }
 
-func betweenPi(value float64) float64 {
-	for value > math.Pi {
-		value -= 2 * math.Pi
+// controlQuaternion converts the raw (unwrapped) Euler sample at the given
+// orientation control-point index into a quaternion. Sampling the
+// activity-driven orientation splines at their own control-point grid
+// rather than at arbitrary t keeps OrientationQuat's SLERP keyframes tied to
+// the same random walk that has always driven orientation.
+func (s *Simulator) controlQuaternion(index float64) Quaternion {
+	return QuaternionFromEuler(
+		s.orientationCurveX.At(index).Y,
+		s.orientationCurveY.At(index).Y,
+		s.orientationCurveZ.At(index).Y,
+	)
+}
+
+// OrientationQuat returns the device's orientation at t as a unit
+// quaternion, produced by spherically interpolating (SLERP) between the
+// control quaternions bracketing t.
+func (s *Simulator) OrientationQuat(t time.Time) Quaternion {
+	if t.Before(s.start) {
+		t = s.start
 	}
-	for value < -math.Pi {
-		value += 2 * math.Pi
+	if t.After(s.end) {
+		t = s.end
 	}
-	return value
+	i := float64(t.Sub(s.start).Milliseconds()) / float64(s.orientationInterval)
+	i0 := math.Floor(i)
+	q0 := s.controlQuaternion(i0)
+	q1 := s.controlQuaternion(i0 + 1)
+	return Slerp(q0, q1, i-i0)
 }
 
 func between(value, min, max float64) float64 {
@@ -0,0 +1,286 @@
+package sensor
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// StreamOptions configures Manager.Stream.
+type StreamOptions struct {
+	// Sensors is the set of sensor types to stream. If empty, Accelerometer,
+	// Gyroscope, and Magnetometer are streamed.
+	Sensors []Type
+
+	// ODR overrides the output data rate, in Hz, for specific sensor types.
+	// Sensor types absent here fall back to DefaultODR.
+	ODR map[Type]float64
+
+	// Jitter is the maximum random timing jitter applied to each sample,
+	// expressed as a fraction of its sampling interval (e.g. 0.1 for ±10%).
+	Jitter float64
+
+	// DropoutProbability is the per-sample probability, in [0, 1], that a
+	// sample is silently skipped, emulating a bus error or missed
+	// interrupt.
+	DropoutProbability float64
+}
+
+// Stream emits Events for the configured sensors at their output data
+// rates, starting now and running until the Manager's End is reached or ctx
+// is canceled, at which point the returned channel is closed.
+func (s *Manager) Stream(ctx context.Context, opts StreamOptions) <-chan Event {
+	sensors := opts.Sensors
+	if len(sensors) == 0 {
+		sensors = []Type{Accelerometer, Gyroscope, Magnetometer}
+	}
+
+	out := make(chan Event)
+	done := make(chan struct{}, len(sensors))
+	for _, st := range sensors {
+		odr := DefaultODR[st]
+		if o, ok := opts.ODR[st]; ok {
+			odr = o
+		}
+		if odr <= 0 {
+			done <- struct{}{}
+			continue
+		}
+		go func(st Type, odr float64) {
+			defer func() { done <- struct{}{} }()
+			s.streamSensor(ctx, st, odr, opts, out)
+		}(st, odr)
+	}
+
+	go func() {
+		for range sensors {
+			<-done
+		}
+		close(out)
+	}()
+
+	return out
+}
+
+// streamSensor samples st at odr Hz, advancing through [Manager.Start,
+// Manager.End] in lockstep with wall-clock time, until that window is
+// exhausted or ctx is canceled.
+func (s *Manager) streamSensor(ctx context.Context, st Type, odr float64, opts StreamOptions, out chan<- Event) {
+	interval := time.Duration(float64(time.Second) / odr)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	streamStart := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			simTime := s.start.Add(time.Since(streamStart))
+			if simTime.After(s.end) {
+				return
+			}
+			if opts.DropoutProbability > 0 && rand.Float64() < opts.DropoutProbability {
+				continue
+			}
+			if opts.Jitter > 0 {
+				jitter := (rand.Float64()*2 - 1) * opts.Jitter * float64(interval)
+				simTime = simTime.Add(time.Duration(jitter))
+			}
+			select {
+			case out <- s.Get(st, simTime):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// recordedEvent is the JSONL encoding of an Event.
+type recordedEvent struct {
+	TimestampNS int64     `json:"timestamp_ns"`
+	Sensor      string    `json:"sensor"`
+	Data        []float64 `json:"data"`
+}
+
+var csvHeader = []string{"timestamp_ns", "sensor", "x", "y", "z"}
+
+// Recorder serializes a stream of Events to CSV and/or JSONL, matching the
+// schema most public IMU datasets use: timestamp_ns, sensor, x, y, z.
+type Recorder struct {
+	csv   *csv.Writer
+	jsonl *json.Encoder
+}
+
+// NewRecorder returns a Recorder writing to csvOut and jsonlOut. Either may
+// be nil to skip that format.
+func NewRecorder(csvOut, jsonlOut io.Writer) (*Recorder, error) {
+	r := &Recorder{}
+	if csvOut != nil {
+		r.csv = csv.NewWriter(csvOut)
+		if err := r.csv.Write(csvHeader); err != nil {
+			return nil, err
+		}
+	}
+	if jsonlOut != nil {
+		r.jsonl = json.NewEncoder(jsonlOut)
+	}
+	return r, nil
+}
+
+// Write appends event to every configured output.
+func (r *Recorder) Write(event Event) error {
+	var x, y, z float64
+	if len(event.Data) == 3 {
+		x, y, z = event.Data[0], event.Data[1], event.Data[2]
+	}
+
+	if r.csv != nil {
+		row := []string{
+			strconv.FormatInt(event.Timestamp.UnixNano(), 10),
+			event.Sensor.String(),
+			strconv.FormatFloat(x, 'f', -1, 64),
+			strconv.FormatFloat(y, 'f', -1, 64),
+			strconv.FormatFloat(z, 'f', -1, 64),
+		}
+		if err := r.csv.Write(row); err != nil {
+			return err
+		}
+	}
+	if r.jsonl != nil {
+		if err := r.jsonl.Encode(recordedEvent{
+			TimestampNS: event.Timestamp.UnixNano(),
+			Sensor:      event.Sensor.String(),
+			Data:        event.Data,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush flushes any buffered CSV output.
+func (r *Recorder) Flush() error {
+	if r.csv == nil {
+		return nil
+	}
+	r.csv.Flush()
+	return r.csv.Error()
+}
+
+// Record writes every Event read from ch until ch is closed or ctx is
+// canceled, then flushes.
+func (r *Recorder) Record(ctx context.Context, ch <-chan Event) error {
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return r.Flush()
+			}
+			if err := r.Write(event); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Player replays a recorded CSV or JSONL trace through the same channel
+// type Manager.Stream produces.
+type Player struct {
+	events []Event
+}
+
+// NewPlayerFromCSV parses a CSV trace written by Recorder.
+func NewPlayerFromCSV(r io.Reader) (*Player, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return &Player{}, nil
+	}
+
+	events := make([]Event, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		ts, err := strconv.ParseInt(row[0], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		st, err := parseSensorType(row[1])
+		if err != nil {
+			return nil, err
+		}
+		data := make([]float64, 3)
+		for i := range data {
+			if data[i], err = strconv.ParseFloat(row[2+i], 64); err != nil {
+				return nil, err
+			}
+		}
+		events = append(events, Event{Sensor: st, Timestamp: time.Unix(0, ts), Data: data})
+	}
+	return &Player{events: events}, nil
+}
+
+// NewPlayerFromJSONL parses a JSONL trace written by Recorder.
+func NewPlayerFromJSONL(r io.Reader) (*Player, error) {
+	var events []Event
+	dec := json.NewDecoder(r)
+	for dec.More() {
+		var rec recordedEvent
+		if err := dec.Decode(&rec); err != nil {
+			return nil, err
+		}
+		st, err := parseSensorType(rec.Sensor)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, Event{Sensor: st, Timestamp: time.Unix(0, rec.TimestampNS), Data: rec.Data})
+	}
+	return &Player{events: events}, nil
+}
+
+// Play replays every recorded Event, in original order and spacing scaled
+// by speed (1 for real time, <=0 to replay as fast as possible), through the
+// returned channel. It stops early and closes the channel if ctx is
+// canceled.
+func (p *Player) Play(ctx context.Context, speed float64) <-chan Event {
+	out := make(chan Event)
+	go func() {
+		defer close(out)
+		var last time.Time
+		for i, event := range p.events {
+			if i > 0 && speed > 0 {
+				gap := time.Duration(float64(event.Timestamp.Sub(last)) / speed)
+				select {
+				case <-time.After(gap):
+				case <-ctx.Done():
+					return
+				}
+			}
+			last = event.Timestamp
+			select {
+			case out <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// parseSensorType reverses Type.String, used when parsing a recorded trace.
+func parseSensorType(name string) (Type, error) {
+	for st, n := range sensorNames {
+		if n == name {
+			return st, nil
+		}
+	}
+	return 0, fmt.Errorf("sensor: unknown sensor type %q", name)
+}
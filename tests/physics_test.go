@@ -0,0 +1,228 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/robin-samuel/sensor"
+	"github.com/robin-samuel/spline"
+)
+
+// zeroProfile is a motionless, perfectly level ActivityProfile used to pin
+// down physical invariants that are hard to see in the random-walk output
+// of NewManager.
+type zeroProfile struct{}
+
+func (zeroProfile) Position(axis int, d time.Duration) []spline.Point {
+	return []spline.Point{{X: 0, Y: 0}, {X: float64(d.Milliseconds()), Y: 0}}
+}
+
+func (zeroProfile) Orientation(axis int, d time.Duration) []spline.Point {
+	return []spline.Point{{X: 0, Y: 0}, {X: float64(d.Milliseconds()), Y: 0}}
+}
+
+// controlPointProfile pins the orientation spline's control points at
+// index1 and index2 (pitch, roll, yaw, per axis) to exact values, so a test
+// can reconstruct precisely which quaternions the Simulator's SLERP will
+// interpolate between. All other control points are held at zero.
+type controlPointProfile struct {
+	index1, index2     int
+	atIndex1, atIndex2 [3]float64
+}
+
+func (p controlPointProfile) Position(axis int, d time.Duration) []spline.Point {
+	return []spline.Point{{X: 0, Y: 0}, {X: float64(d.Milliseconds()), Y: 0}}
+}
+
+func (p controlPointProfile) Orientation(axis int, d time.Duration) []spline.Point {
+	const interval = 100 // matches the package's default orientation control-point spacing
+	var points []spline.Point
+	for i := 0; i*interval < int(d.Milliseconds()); i++ {
+		value := 0.0
+		switch i {
+		case p.index1:
+			value = p.atIndex1[axis]
+		case p.index2:
+			value = p.atIndex2[axis]
+		}
+		points = append(points, spline.Point{X: float64(i * interval), Y: value})
+	}
+	return points
+}
+
+// TestGyroscopeReportsBodyFrameAngularVelocity checks that Manager.Get's
+// Gyroscope case reports a rotation about the device's own body-x axis on
+// the x channel, regardless of the device's current heading - not on
+// whichever world axis that heading happens to align x with.
+func TestGyroscopeReportsBodyFrameAngularVelocity(t *testing.T) {
+	const orientationInterval = 100 * time.Millisecond
+	dt := 66 * time.Millisecond
+	rate := 1.0 // rad/s, about the device's own body-x axis; kept small so
+	// the 2*sin(theta/2)/dt discretization in Get closely tracks rate
+
+	// q0Target: the device yawed 90 degrees from its reference heading.
+	q0Target := sensor.QuaternionFromEuler(0, 0, math.Pi/2)
+
+	// Get samples t-dt and t, which SLERPs to a fraction frac of the way
+	// from control point 1 to control point 2. Scale the full control-to-
+	// control rotation so that fraction reads back as rate.
+	frac := float64(dt) / float64(orientationInterval)
+	fullAngle := rate * dt.Seconds() / frac
+	bodyRotation := sensor.Quaternion{W: math.Cos(fullAngle / 2), X: math.Sin(fullAngle / 2)}
+	q2Target := q0Target.Multiply(bodyRotation)
+
+	p0, r0, y0 := q0Target.Euler()
+	p1, r1, y1 := q2Target.Euler()
+
+	start := time.Now()
+	end := start.Add(5 * time.Second)
+
+	// NewManagerFromProfile pads the Simulator's own start a second earlier
+	// than start, so the control-point index its queries land on is offset
+	// by a full second's worth of intervals from what start.Add(...) alone
+	// would suggest.
+	indexOffset := int(time.Second / orientationInterval)
+	profile := controlPointProfile{
+		index1:   indexOffset + 1,
+		index2:   indexOffset + 2,
+		atIndex1: [3]float64{p0, r0, y0},
+		atIndex2: [3]float64{p1, r1, y1},
+	}
+	m := sensor.NewManagerFromProfile(start, end, profile, sensor.WithNoiseModel(sensor.NoNoiseModel))
+
+	// t-dt lands exactly on control point 1 (q0Target); t lands frac of the
+	// way to control point 2.
+	event := m.Get(sensor.Gyroscope, start.Add(orientationInterval+dt))
+
+	if math.Abs(event.Data[0]-rate) > 1e-3 {
+		t.Fatalf("expected ~%v rad/s on body x, got %v", rate, event.Data[0])
+	}
+	if math.Abs(event.Data[1]) > 1e-3 || math.Abs(event.Data[2]) > 1e-3 {
+		t.Fatalf("expected no angular velocity on y/z for a pure body-x rotation, got %v", event.Data)
+	}
+}
+
+// TestAccelerometerStationaryReadsGravity checks that a motionless, level
+// device reads +g on the up axis (z, in the default ENU reference frame)
+// rather than 0 or -g.
+func TestAccelerometerStationaryReadsGravity(t *testing.T) {
+	start := time.Now()
+	end := start.Add(5 * time.Second)
+	m := sensor.NewManagerFromProfile(start, end, zeroProfile{}, sensor.WithNoiseModel(sensor.NoNoiseModel))
+
+	event := m.Get(sensor.Accelerometer, start.Add(2*time.Second))
+	if len(event.Data) != 3 {
+		t.Fatalf("expected 3-axis data, got %v", event.Data)
+	}
+	if math.Abs(event.Data[0]) > 1e-6 || math.Abs(event.Data[1]) > 1e-6 {
+		t.Fatalf("expected no acceleration on x/y for a level, motionless device, got %v", event.Data)
+	}
+	if math.Abs(event.Data[2]-9.80665) > 1e-6 {
+		t.Fatalf("expected ~9.80665 m/s^2 on z (up) for a level, motionless device, got %v", event.Data[2])
+	}
+}
+
+// constantProfile is a motionless ActivityProfile reporting a fixed
+// position on every axis, used to probe Timeline's handling of gaps
+// between segments.
+type constantProfile struct {
+	value float64
+}
+
+func (p constantProfile) Position(axis int, d time.Duration) []spline.Point {
+	return []spline.Point{{X: 0, Y: p.value}, {X: float64(d.Milliseconds()), Y: p.value}}
+}
+
+func (p constantProfile) Orientation(axis int, d time.Duration) []spline.Point {
+	return []spline.Point{{X: 0, Y: 0}, {X: float64(d.Milliseconds()), Y: 0}}
+}
+
+// TestNoiseModelBiasDriftsOverTime checks that DefaultNoiseModel's Gauss-
+// Markov bias term actually walks over the course of a run, rather than
+// locking to its first control point's value for every timestamp past the
+// first control-point interval.
+func TestNoiseModelBiasDriftsOverTime(t *testing.T) {
+	start := time.Now()
+	end := start.Add(10 * time.Minute)
+
+	params := map[sensor.Type]sensor.NoiseParams{
+		sensor.Accelerometer: {
+			BiasInstability:     1,
+			BiasCorrelationTime: 100 * time.Second,
+			Misalignment:        sensor.IdentityMatrix3(),
+			ODR:                 200,
+		},
+	}
+	model := sensor.NewDefaultNoiseModel(start, end, params)
+
+	early := model.Apply(sensor.Accelerometer, start.Add(30*time.Second), []float64{0, 0, 0})
+	late := model.Apply(sensor.Accelerometer, start.Add(9*time.Minute), []float64{0, 0, 0})
+
+	same := true
+	for i := range early {
+		if math.Abs(early[i]-late[i]) > 1e-9 {
+			same = false
+		}
+	}
+	if same {
+		t.Fatalf("expected bias to drift over 9 minutes of a 100s-correlation-time random walk, got identical readings %v at both ends", early)
+	}
+}
+
+// TestMagnetometerAppliesSoftIronBeforeHardIron checks that the simulated
+// magnetometer reading is distorted as A*field + b - the soft-iron matrix
+// applied to the clean field, then the hard-iron offset added afterward -
+// rather than scaling the offset through the soft-iron matrix too.
+func TestMagnetometerAppliesSoftIronBeforeHardIron(t *testing.T) {
+	softIron := sensor.Matrix3{
+		{2, 0, 0},
+		{0, 1, 0},
+		{0, 0, 1},
+	}
+	hardIron := sensor.Vector3{1, 2, 3}
+
+	start := time.Now()
+	end := start.Add(time.Second)
+	m := sensor.NewManagerFromProfile(start, end, zeroProfile{},
+		sensor.WithNoiseModel(sensor.NoNoiseModel),
+		sensor.WithMagneticField(49, 1.05, 0),
+		sensor.WithSoftIron(softIron),
+		sensor.WithHardIron(hardIron[0], hardIron[1], hardIron[2]),
+	)
+
+	event := m.Get(sensor.Magnetometer, start)
+
+	// The device is level and at its reference heading, so the body field
+	// equals the world field, converted from NED to the default ENU
+	// reference frame.
+	const f, incl = 49, 1.05
+	horizontal := f * math.Cos(incl)
+	ned := sensor.Vector3{horizontal, 0, f * math.Sin(incl)}
+	worldField := sensor.Vector3{ned[1], ned[0], -ned[2]}
+	want := softIron.Apply(worldField).Add(hardIron)
+
+	for i := range want {
+		if math.Abs(event.Data[i]-want[i]) > 1e-6 {
+			t.Fatalf("expected A*field+b = %v, got %v", want, event.Data)
+		}
+	}
+}
+
+// TestTimelineHoldsFlatAcrossGaps checks that a gap between two Timeline
+// segments holds the prior segment's last value, per Add's doc comment,
+// rather than drifting toward the next segment across the gap.
+func TestTimelineHoldsFlatAcrossGaps(t *testing.T) {
+	start := time.Now()
+	tl := sensor.NewTimeline().
+		Add(0, time.Second, constantProfile{value: 100}).
+		Add(3*time.Second, 4*time.Second, constantProfile{value: -100})
+
+	sim := sensor.NewSimulatorFromProfile(start, start.Add(4*time.Second), tl)
+
+	// 2s falls in the gap between the two segments.
+	pos := sim.Position(start.Add(2 * time.Second))
+	if math.Abs(pos.Values[0]-100) > 1e-3 {
+		t.Fatalf("expected the gap to hold flat at the first segment's value (100), got %v", pos.Values[0])
+	}
+}
@@ -102,7 +102,7 @@ func TestSensor(t *testing.T) {
 	for t := start; t.Before(end); t = t.Add(time.Millisecond * 66) {
 		aEvents = append(aEvents, s.Get(sensor.Accelerometer, t))
 		gEvents = append(gEvents, s.Get(sensor.Gyroscope, t))
-		// mEvents = append(mEvents, s.Get(sensor.Magnetometer, t))
+		mEvents = append(mEvents, s.Get(sensor.Magnetometer, t))
 	}
 
 	p := plot.New()
@@ -0,0 +1,45 @@
+package sensor
+
+// Vector3 is a 3-element vector used for body- and world-frame quantities
+// throughout the package.
+type Vector3 [3]float64
+
+// Matrix3 is a 3x3 row-major matrix.
+type Matrix3 [3][3]float64
+
+// IdentityMatrix3 returns the 3x3 identity matrix.
+func IdentityMatrix3() Matrix3 {
+	return Matrix3{
+		{1, 0, 0},
+		{0, 1, 0},
+		{0, 0, 1},
+	}
+}
+
+// Apply returns m*v.
+func (m Matrix3) Apply(v Vector3) Vector3 {
+	return Vector3{
+		m[0][0]*v[0] + m[0][1]*v[1] + m[0][2]*v[2],
+		m[1][0]*v[0] + m[1][1]*v[1] + m[1][2]*v[2],
+		m[2][0]*v[0] + m[2][1]*v[1] + m[2][2]*v[2],
+	}
+}
+
+// Transpose returns the transpose of m.
+func (m Matrix3) Transpose() Matrix3 {
+	return Matrix3{
+		{m[0][0], m[1][0], m[2][0]},
+		{m[0][1], m[1][1], m[2][1]},
+		{m[0][2], m[1][2], m[2][2]},
+	}
+}
+
+// Add returns the element-wise sum of v and w.
+func (v Vector3) Add(w Vector3) Vector3 {
+	return Vector3{v[0] + w[0], v[1] + w[1], v[2] + w[2]}
+}
+
+// Subtract returns the element-wise difference v - w.
+func (v Vector3) Subtract(w Vector3) Vector3 {
+	return Vector3{v[0] - w[0], v[1] - w[1], v[2] - w[2]}
+}